@@ -3617,9 +3617,17 @@ func (nc *Conn) unsubscribe(sub *Subscription, max int, drainMode bool) error {
 
 	maxStr := _EMPTY_
 	if max > 0 {
+		s.mu.Lock()
 		s.max = uint64(max)
-		maxStr = strconv.Itoa(max)
-	} else if !drainMode {
+		// If the number of delivered msgs already reached the max,
+		// unsubscribe now instead of waiting for a message that
+		// may never come.
+		if s.delivered < s.max {
+			maxStr = strconv.Itoa(max)
+		}
+		s.mu.Unlock()
+	}
+	if maxStr == _EMPTY_ && !drainMode {
 		nc.removeSub(s)
 	}
 
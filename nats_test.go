@@ -923,6 +923,70 @@ func TestParserSplitMsg(t *testing.T) {
 	}
 }
 
+func TestParserSplitAtAnyBoundary(t *testing.T) {
+	hdr := "NATS/1.0\r\nk: v\r\n\r\n"
+	stream := []byte("MSG foo 1 5\r\nhello\r\n" +
+		"PING\r\n" +
+		"MSG foo 1 bar 0\r\n\r\n" +
+		"+OK\r\n" +
+		fmt.Sprintf("HMSG foo 1 bar %d %d\r\n%sworld\r\n", len(hdr), len(hdr)+5, hdr) +
+		"PONG\r\n" +
+		"MSG  foo\t1  5 \r\nhello\r\n")
+
+	type expected struct {
+		reply string
+		data  string
+		hval  string
+	}
+	expectedMsgs := []expected{
+		{"", "hello", ""},
+		{"bar", "", ""},
+		{"bar", "world", "v"},
+		{"", "hello", ""},
+	}
+
+	// Feed the same stream using every possible chunk size, including
+	// one byte at a time, so that every control line and payload gets
+	// split at every possible position across calls to parse.
+	for chunk := 1; chunk <= len(stream); chunk++ {
+		nc := &Conn{}
+		nc.bw = bufio.NewWriterSize(&bytes.Buffer{}, nc.Opts.ReconnectBufSize)
+		nc.ps = &parseState{}
+		sub := &Subscription{Subject: "foo", sid: 1, conn: nc, typ: ChanSubscription}
+		sub.mch = make(chan *Msg, len(expectedMsgs))
+		nc.subs = map[int64]*Subscription{1: sub}
+
+		for start := 0; start < len(stream); start += chunk {
+			end := start + chunk
+			if end > len(stream) {
+				end = len(stream)
+			}
+			if err := nc.parse(stream[start:end]); err != nil {
+				t.Fatalf("Chunk size %d: parser error: %v", chunk, err)
+			}
+		}
+		if nc.ps.state != OP_START {
+			t.Fatalf("Chunk size %d: wrong state: %v", chunk, nc.ps.state)
+		}
+		if nc.ps.argBuf != nil || nc.ps.msgBuf != nil {
+			t.Fatalf("Chunk size %d: buffers should be nil now", chunk)
+		}
+		if n := len(sub.mch); n != len(expectedMsgs) {
+			t.Fatalf("Chunk size %d: expected %d msgs, got %d", chunk, len(expectedMsgs), n)
+		}
+		for i, e := range expectedMsgs {
+			m := <-sub.mch
+			if m.Subject != "foo" || m.Reply != e.reply || string(m.Data) != e.data {
+				t.Fatalf("Chunk size %d: msg %d unexpected: subject=%q reply=%q data=%q",
+					chunk, i, m.Subject, m.Reply, m.Data)
+			}
+			if hv := m.Header.Get("k"); hv != e.hval {
+				t.Fatalf("Chunk size %d: msg %d expected header value %q, got %q", chunk, i, e.hval, hv)
+			}
+		}
+	}
+}
+
 func TestNormalizeError(t *testing.T) {
 	expected := "Typical Error"
 	if s := normalizeErr("-ERR '" + expected + "'"); s != expected {
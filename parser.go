@@ -27,14 +27,16 @@ type msgArg struct {
 
 const MAX_CONTROL_LINE_SIZE = 4096
 
+// parseState holds the parser state that persists between calls to parse,
+// so that a protocol line or payload split across reads can be resumed.
 type parseState struct {
-	state   int
-	as      int
-	drop    int
-	hdr     int
-	ma      msgArg
-	argBuf  []byte
-	msgBuf  []byte
+	state   int    // current position in the protocol state machine
+	as      int    // start of the current argument or payload in buf
+	drop    int    // number of trailing bytes ('\r') to drop from an argument
+	hdr     int    // -1 for MSG, 0 or more for HMSG
+	ma      msgArg // arguments of the MSG/HMSG being processed
+	argBuf  []byte // control line arguments accumulated across reads
+	msgBuf  []byte // payload accumulated across reads
 	scratch [MAX_CONTROL_LINE_SIZE]byte
 }
 
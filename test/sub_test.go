@@ -136,6 +136,68 @@ func TestClientASyncAutoUnsub(t *testing.T) {
 	}
 }
 
+func TestAutoUnsubAfterMaxDelivered(t *testing.T) {
+	s := RunDefaultServer()
+	defer s.Shutdown()
+
+	nc := NewDefaultConnection(t)
+	defer nc.Close()
+
+	max := 5
+	received := int32(0)
+	ch := make(chan bool, max)
+	asub, err := nc.Subscribe("foo", func(_ *nats.Msg) {
+		atomic.AddInt32(&received, 1)
+		ch <- true
+	})
+	if err != nil {
+		t.Fatal("Failed to subscribe: ", err)
+	}
+	ssub, err := nc.SubscribeSync("foo")
+	if err != nil {
+		t.Fatal("Failed to subscribe: ", err)
+	}
+	for i := 0; i < max; i++ {
+		nc.Publish("foo", []byte("Hello"))
+	}
+	nc.Flush()
+	for i := 0; i < max; i++ {
+		if err := Wait(ch); err != nil {
+			t.Fatal("Did not get our message")
+		}
+		if _, err := ssub.NextMsg(time.Second); err != nil {
+			t.Fatalf("Error on NextMsg: %v", err)
+		}
+	}
+
+	// Setting a max that has already been reached should unsubscribe right away.
+	if err := asub.AutoUnsubscribe(max); err != nil {
+		t.Fatalf("Error on AutoUnsubscribe: %v", err)
+	}
+	if err := ssub.AutoUnsubscribe(max); err != nil {
+		t.Fatalf("Error on AutoUnsubscribe: %v", err)
+	}
+	if asub.IsValid() || ssub.IsValid() {
+		t.Fatal("Expected subscriptions to be invalid after max already reached")
+	}
+	if n := nc.NumSubscriptions(); n != 0 {
+		t.Fatalf("Expected no subscription, got %d", n)
+	}
+	if _, err := ssub.NextMsg(10 * time.Millisecond); err != nats.ErrMaxMessages {
+		t.Fatalf("Expected '%v', but got: '%v'", nats.ErrMaxMessages, err)
+	}
+
+	// Check that the server no longer delivers to us.
+	for i := 0; i < max; i++ {
+		nc.Publish("foo", []byte("Hello"))
+	}
+	nc.Flush()
+	time.Sleep(50 * time.Millisecond)
+	if n := atomic.LoadInt32(&received); n != int32(max) {
+		t.Fatalf("Received %d msgs, wanted only %d", n, max)
+	}
+}
+
 func TestAutoUnsubAndReconnect(t *testing.T) {
 	s := RunDefaultServer()
 	defer s.Shutdown()